@@ -15,7 +15,9 @@ package cytoscape
 
 import (
 	"crypto/md5"
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"sort"
 
 	"github.com/kiali/kiali/graph"
@@ -28,31 +30,33 @@ type NodeData struct {
 	Parent string `json:"parent,omitempty"` // Compound Node parent ID
 
 	// App Fields (not required by Cytoscape)
-	NodeType        string          `json:"nodeType"`
-	Namespace       string          `json:"namespace"`
-	Workload        string          `json:"workload,omitempty"`
-	App             string          `json:"app,omitempty"`
-	Version         string          `json:"version,omitempty"`
-	Service         string          `json:"service,omitempty"`         // requested service for NodeTypeService
-	DestServices    map[string]bool `json:"destServices,omitempty"`    // requested services for [dest] node
-	HttpIn          string          `json:"httpIn,omitempty"`          // incoming edge aggregate, requests per second, 2 digit precision
-	HttpIn3xx       string          `json:"httpIn3XX,omitempty"`       // incoming edge aggregate, requests per second, 2 digit precision
-	HttpIn4xx       string          `json:"httpIn4XX,omitempty"`       // incoming edge aggregate, requests per second, 2 digit precision
-	HttpIn5xx       string          `json:"httpIn5XX,omitempty"`       // incoming edge aggregate, requests per second, 2 digit precision
-	HttpOut         string          `json:"httpOut,omitempty"`         // outgoing edge aggregate, requests per second, 2 digit precision
-	TcpIn           string          `json:"tcpIn,omitempty"`           // incoming edge aggregate, bytes per second, 2 digit precision
-	TcpOut          string          `json:"tcpOut,omitempty"`          // outgoing edge aggregate, bytes per second, 2 digit precision
-	HasCB           bool            `json:"hasCB,omitempty"`           // true (has circuit breaker) | false
-	HasMissingSC    bool            `json:"hasMissingSC,omitempty"`    // true (has missing sidecar) | false
-	HasVS           bool            `json:"hasVS,omitempty"`           // true (has route rule) | false
-	IsDead          bool            `json:"isDead,omitempty"`          // true (has no pods) | false
-	IsGroup         string          `json:"isGroup,omitempty"`         // set to the grouping type, current values: [ 'app', 'version' ]
-	IsInaccessible  bool            `json:"isInaccessible,omitempty"`  // true if the node exists in an inaccessible namespace
-	IsMisconfigured string          `json:"isMisconfigured,omitempty"` // set to misconfiguration list, current values: [ 'labels' ]
-	IsOutside       bool            `json:"isOutside,omitempty"`       // true | false
-	IsRoot          bool            `json:"isRoot,omitempty"`          // true | false
-	IsServiceEntry  string          `json:"isServiceEntry,omitempty"`  // set to the location, current values: [ 'MESH_EXTERNAL', 'MESH_INTERNAL' ]
-	IsUnused        bool            `json:"isUnused,omitempty"`        // true | false
+	NodeType         string          `json:"nodeType"`
+	Cluster          string          `json:"cluster,omitempty"` // the cluster/peer the node belongs to, for multicluster/federated meshes
+	Namespace        string          `json:"namespace"`
+	Workload         string          `json:"workload,omitempty"`
+	App              string          `json:"app,omitempty"`
+	Version          string          `json:"version,omitempty"`
+	Service          string          `json:"service,omitempty"`          // requested service for NodeTypeService
+	DestServices     map[string]bool `json:"destServices,omitempty"`     // requested services for [dest] node
+	HttpIn           string          `json:"httpIn,omitempty"`           // incoming edge aggregate, requests per second, 2 digit precision
+	HttpIn3xx        string          `json:"httpIn3XX,omitempty"`        // incoming edge aggregate, requests per second, 2 digit precision
+	HttpIn4xx        string          `json:"httpIn4XX,omitempty"`        // incoming edge aggregate, requests per second, 2 digit precision
+	HttpIn5xx        string          `json:"httpIn5XX,omitempty"`        // incoming edge aggregate, requests per second, 2 digit precision
+	HttpOut          string          `json:"httpOut,omitempty"`          // outgoing edge aggregate, requests per second, 2 digit precision
+	TcpIn            string          `json:"tcpIn,omitempty"`            // incoming edge aggregate, bytes per second, 2 digit precision
+	TcpOut           string          `json:"tcpOut,omitempty"`           // outgoing edge aggregate, bytes per second, 2 digit precision
+	HasCB            bool            `json:"hasCB,omitempty"`            // true (has circuit breaker) | false
+	HasMissingSC     bool            `json:"hasMissingSC,omitempty"`     // true (has missing sidecar) | false
+	HasVS            bool            `json:"hasVS,omitempty"`            // true (has route rule) | false
+	IsDead           bool            `json:"isDead,omitempty"`           // true (has no pods) | false
+	IsGroup          string          `json:"isGroup,omitempty"`          // set to the grouping type, current values: [ 'app', 'version', 'cluster' ]
+	IsInaccessible   bool            `json:"isInaccessible,omitempty"`   // true if the node exists in an inaccessible namespace
+	IsMisconfigured  string          `json:"isMisconfigured,omitempty"`  // set to misconfiguration list, current values: [ 'labels' ]
+	IsOutside        bool            `json:"isOutside,omitempty"`        // true | false
+	IsRoot           bool            `json:"isRoot,omitempty"`           // true | false
+	IsServiceEntry   string          `json:"isServiceEntry,omitempty"`   // set to the location, current values: [ 'MESH_EXTERNAL', 'MESH_INTERNAL' ]
+	IsUnused         bool            `json:"isUnused,omitempty"`         // true | false
+	OutlierDetection bool            `json:"outlierDetection,omitempty"` // true (has outlier detection / consistent-hash config from a DestinationRule) | false
 }
 
 type EdgeData struct {
@@ -62,16 +66,38 @@ type EdgeData struct {
 	Target string `json:"target"` // child node ID
 
 	// App Fields (not required by Cytoscape)
-	Http           string `json:"http,omitempty"`           // requests per second, 2 digit precision
-	Http3xx        string `json:"http3XX,omitempty"`        // requests per second, 2 digit precision
-	Http4xx        string `json:"http4XX,omitempty"`        // requests per second, 2 digit precision
-	Http5xx        string `json:"http5XX,omitempty"`        // requests per second, 2 digit precision
-	HttpPercentErr string `json:"httpPercentErr,omitempty"` // percent of error responses, 1 digit precision
-	HttpPercentReq string `json:"httpPercentReq,omitempty"` // percent of total parent requests, 1 digit precision
-	ResponseTime   string `json:"responseTime,omitempty"`   // in millis
-	IsMTLS         bool   `json:"isMTLS,omitempty"`         // true (mutual TLS connection) | false
-	IsUnused       bool   `json:"isUnused,omitempty"`       // true | false
-	Tcp            string `json:"tcp,omitempty"`            // bytes per second, 2 digit precision
+	Http                    string                   `json:"http,omitempty"`                    // requests per second, 2 digit precision
+	Http3xx                 string                   `json:"http3XX,omitempty"`                 // requests per second, 2 digit precision
+	Http4xx                 string                   `json:"http4XX,omitempty"`                 // requests per second, 2 digit precision
+	Http5xx                 string                   `json:"http5XX,omitempty"`                 // requests per second, 2 digit precision
+	HttpPercentErr          string                   `json:"httpPercentErr,omitempty"`          // percent of error responses, 1 digit precision
+	HttpPercentReq          string                   `json:"httpPercentReq,omitempty"`          // percent of total parent requests, 1 digit precision
+	ResponseTime            string                   `json:"responseTime,omitempty"`            // Deprecated: mean latency in millis, use ResponseTimePercentiles
+	ResponseTimePercentiles *ResponseTimePercentiles `json:"responseTimePercentiles,omitempty"` // p50/p90/p95/p99 latency, in millis
+	IsMTLS                  bool                     `json:"isMTLS,omitempty"`                  // true (mutual TLS connection) | false
+	IsUnused                bool                     `json:"isUnused,omitempty"`                // true | false
+	Tcp                     string                   `json:"tcp,omitempty"`                     // bytes per second, 2 digit precision
+	IsCrossCluster          bool                     `json:"isCrossCluster,omitempty"`          // true if source and destination clusters differ
+	DestPeer                string                   `json:"destPeer,omitempty"`                // peer name, set when the destination is a federated/peer service
+	LBPolicy                string                   `json:"lbPolicy,omitempty"`                // e.g. round_robin, least_request, ring_hash, maglev, random
+	HashPolicies            []HashPolicy             `json:"hashPolicies,omitempty"`            // consistent-hash routing keys, used when LBPolicy is ring_hash or maglev
+}
+
+// HashPolicy describes one consistent-hash key used to pick a destination when
+// LBPolicy is ring_hash or maglev, mirroring Envoy's hash policy config.
+type HashPolicy struct {
+	Field    string `json:"field"`              // "header" | "cookie" | "query_parameter"
+	Key      string `json:"key"`                // header/cookie/query_parameter name
+	Terminal bool   `json:"terminal,omitempty"` // true if this policy ends hash computation even if its key is absent
+}
+
+// ResponseTimePercentiles holds the latency distribution for an edge, in millis,
+// string-formatted at 0 digit precision to match ResponseTime's existing style.
+type ResponseTimePercentiles struct {
+	P50 string `json:"p50,omitempty"`
+	P90 string `json:"p90,omitempty"`
+	P95 string `json:"p95,omitempty"`
+	P99 string `json:"p99,omitempty"`
 }
 
 type NodeWrapper struct {
@@ -102,7 +128,39 @@ func edgeHash(from, to, protocol string) string {
 	return fmt.Sprintf("%x", md5.Sum([]byte(fmt.Sprintf("%s.%s.%s", from, to, protocol))))
 }
 
-func NewConfig(trafficMap graph.TrafficMap, o options.VendorOptions) (result Config) {
+// nodeDepths returns, for every node, how many Parent hops separate it from
+// its outermost compound ancestor (0 for a node with no parent). Cycles (which
+// should never occur, but would otherwise loop forever) are broken by treating
+// the repeated node as the root of its chain.
+func nodeDepths(nodes []*NodeWrapper) map[string]int {
+	byId := make(map[string]*NodeWrapper, len(nodes))
+	for _, n := range nodes {
+		byId[n.Data.Id] = n
+	}
+
+	depth := make(map[string]int, len(nodes))
+	for _, n := range nodes {
+		d := 0
+		seen := map[string]bool{n.Data.Id: true}
+		cur := n
+		for cur.Data.Parent != "" {
+			parent, ok := byId[cur.Data.Parent]
+			if !ok || seen[parent.Data.Id] {
+				break
+			}
+			seen[parent.Data.Id] = true
+			d++
+			cur = parent
+		}
+		depth[n.Data.Id] = d
+	}
+	return depth
+}
+
+// BuildElements runs the graph-to-vendor-model pipeline (build, grouping, sort)
+// and returns the intermediate node/edge model shared by every renderer that
+// consumes a graph.TrafficMap (Cytoscape JSON, GraphViz DOT, GEXF, ...).
+func BuildElements(trafficMap graph.TrafficMap, o options.VendorOptions) Elements {
 	nodes := []*NodeWrapper{}
 	edges := []*EdgeWrapper{}
 
@@ -118,14 +176,25 @@ func NewConfig(trafficMap graph.TrafficMap, o options.VendorOptions) (result Con
 		if o.GraphType == graph.GraphTypeVersionedApp {
 			groupByVersion(&nodes)
 		}
+	case options.GroupByCluster:
+		groupByCluster(&nodes)
 	default:
 		// no grouping
 	}
 
 	// sort nodes and edges for better json presentation (and predictable testing)
-	// kiali-1258 compound/isGroup/parent nodes must come before the child references
+	// kiali-1258 compound/isGroup/parent nodes must come before the child references.
+	// A parent's own fields (namespace/cluster/app) can be arbitrary or absent for
+	// a box spanning multiple values (e.g. a multi-namespace cluster box), so field
+	// equality alone can't be trusted to order parents ahead of their children in a
+	// nested compound hierarchy - depth in the Parent chain is sorted first instead.
+	depth := nodeDepths(nodes)
 	sort.Slice(nodes, func(i, j int) bool {
 		switch {
+		case depth[nodes[i].Data.Id] != depth[nodes[j].Data.Id]:
+			return depth[nodes[i].Data.Id] < depth[nodes[j].Data.Id]
+		case nodes[i].Data.Cluster != nodes[j].Data.Cluster:
+			return nodes[i].Data.Cluster < nodes[j].Data.Cluster
 		case nodes[i].Data.Namespace != nodes[j].Data.Namespace:
 			return nodes[i].Data.Namespace < nodes[j].Data.Namespace
 		case nodes[i].Data.IsGroup != nodes[j].Data.IsGroup:
@@ -151,14 +220,37 @@ func NewConfig(trafficMap graph.TrafficMap, o options.VendorOptions) (result Con
 		}
 	})
 
-	elements := Elements{nodes, edges}
-	result = Config{
+	return Elements{nodes, edges}
+}
+
+func NewConfig(trafficMap graph.TrafficMap, o options.VendorOptions) Config {
+	return newConfig(BuildElements(trafficMap, o), o)
+}
+
+func newConfig(elements Elements, o options.VendorOptions) Config {
+	return Config{
 		Duration:  int64(o.Duration.Seconds()),
 		Timestamp: o.QueryTime,
 		GraphType: o.GraphType,
 		Elements:  elements,
 	}
-	return result
+}
+
+// Renderer implements options.Renderer, rendering the shared Elements model as
+// the native Cytoscape JSON config (i.e. what NewConfig already returns).
+type Renderer struct{}
+
+// Render implements options.Renderer.
+func (Renderer) Render(elements interface{}, o options.VendorOptions) ([]byte, error) {
+	el, ok := elements.(Elements)
+	if !ok {
+		return nil, fmt.Errorf("cytoscape: unsupported elements type %T", elements)
+	}
+	return json.Marshal(newConfig(el, o))
+}
+
+func init() {
+	options.RegisterRenderer(options.FormatCytoscape, Renderer{})
 }
 
 func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*EdgeWrapper, o options.VendorOptions) {
@@ -175,6 +267,11 @@ func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*E
 			Service:   n.Service,
 		}
 
+		// node may belong to a cluster other than the default (multicluster/federated mesh)
+		if val, ok := n.Metadata["cluster"]; ok {
+			nd.Cluster = val.(string)
+		}
+
 		addNodeTelemetry(n, nd)
 
 		// node may have deployment but no pods running)
@@ -232,6 +329,11 @@ func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*E
 			nd.IsServiceEntry = val.(string)
 		}
 
+		// node may have outlier detection / consistent-hash config from a DestinationRule
+		if val, ok := n.Metadata["hasOutlierDetection"]; ok {
+			nd.OutlierDetection = val.(bool)
+		}
+
 		nw := NodeWrapper{
 			Data: nd,
 		}
@@ -306,6 +408,68 @@ func getRate(md map[string]interface{}, k string) float64 {
 	return 0.0
 }
 
+// toHashPolicies decodes "hashPolicies" metadata into []HashPolicy. The value
+// is expected as a []interface{} of map[string]interface{} entries (the shape
+// a JSON-decoded boundary naturally produces, as with "destServices"), so the
+// business layer that populates graph.Edge.Metadata never needs to import the
+// cytoscape package just to satisfy this field.
+func toHashPolicies(val interface{}) []HashPolicy {
+	raw, ok := val.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	policies := make([]HashPolicy, 0, len(raw))
+	for _, v := range raw {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		hp := HashPolicy{}
+		if field, ok := m["field"].(string); ok {
+			hp.Field = field
+		}
+		if key, ok := m["key"].(string); ok {
+			hp.Key = key
+		}
+		if terminal, ok := m["terminal"].(bool); ok {
+			hp.Terminal = terminal
+		}
+		policies = append(policies, hp)
+	}
+	return policies
+}
+
+// addResponseTimePercentiles builds the latency distribution for an edge from
+// whichever responseTimeP.. quantiles are present in the edge metadata. It
+// returns nil when none of the quantiles were requested/available.
+func addResponseTimePercentiles(md map[string]interface{}) *ResponseTimePercentiles {
+	rt := ResponseTimePercentiles{}
+	set := false
+
+	if val, ok := md["responseTimeP50"]; ok {
+		rt.P50 = fmt.Sprintf("%.0f", val.(float64))
+		set = true
+	}
+	if val, ok := md["responseTimeP90"]; ok {
+		rt.P90 = fmt.Sprintf("%.0f", val.(float64))
+		set = true
+	}
+	if val, ok := md["responseTimeP95"]; ok {
+		rt.P95 = fmt.Sprintf("%.0f", val.(float64))
+		set = true
+	}
+	if val, ok := md["responseTimeP99"]; ok {
+		rt.P99 = fmt.Sprintf("%.0f", val.(float64))
+		set = true
+	}
+
+	if !set {
+		return nil
+	}
+	return &rt
+}
+
 func addEdgeTelemetry(ed *EdgeData, e *graph.Edge, o options.VendorOptions) {
 	http := getRate(e.Metadata, "http")
 
@@ -335,6 +499,8 @@ func addEdgeTelemetry(ed *EdgeData, e *graph.Edge, o options.VendorOptions) {
 			ed.ResponseTime = fmt.Sprintf("%.0f", responseTime)
 		}
 
+		ed.ResponseTimePercentiles = addResponseTimePercentiles(e.Metadata)
+
 		httpPercentReq := http / getRate(e.Source.Metadata, "httpOut") * 100.0
 		if httpPercentReq < 100.0 {
 			ed.HttpPercentReq = fmt.Sprintf("%.1f", httpPercentReq)
@@ -353,6 +519,28 @@ func addEdgeTelemetry(ed *EdgeData, e *graph.Edge, o options.VendorOptions) {
 	if tcp > 0.0 {
 		ed.Tcp = fmt.Sprintf("%.2f", tcp)
 	}
+
+	// edge may carry the DestinationRule load balancing policy in effect
+	if val, ok := e.Metadata["lbPolicy"]; ok {
+		ed.LBPolicy = val.(string)
+	}
+
+	// edge may carry the consistent-hash keys used for ring_hash/maglev load balancing
+	if val, ok := e.Metadata["hashPolicies"]; ok {
+		ed.HashPolicies = toHashPolicies(val)
+	}
+
+	// mark edges that cross a cluster boundary (multicluster/federated mesh)
+	if srcCluster, ok := e.Source.Metadata["cluster"]; ok {
+		if dstCluster, ok := e.Dest.Metadata["cluster"]; ok && dstCluster.(string) != srcCluster.(string) {
+			ed.IsCrossCluster = true
+		}
+	}
+
+	// preserve the peer name when the destination is a federated/peer service
+	if val, ok := e.Dest.Metadata["peerName"]; ok {
+		ed.DestPeer = val.(string)
+	}
 }
 
 // groupByVersion adds compound nodes to group multiple versions of the same app
@@ -366,7 +554,7 @@ func groupByVersion(nodes *[]*NodeWrapper) {
 		}
 	}
 
-	generateGroupCompoundNodes(appBox, nodes, options.GroupByVersion)
+	generateGroupCompoundNodes(appBox, nodes, options.GroupByVersion, "")
 }
 
 // groupByApp adds compound nodes to group all nodes for the same app
@@ -380,21 +568,68 @@ func groupByApp(nodes *[]*NodeWrapper) {
 		}
 	}
 
-	generateGroupCompoundNodes(appBox, nodes, options.GroupByApp)
+	generateGroupCompoundNodes(appBox, nodes, options.GroupByApp, "")
 }
 
-func generateGroupCompoundNodes(appBox map[string][]*NodeData, nodes *[]*NodeWrapper, groupBy string) {
+// groupByCluster adds compound nodes to group all nodes for the same cluster, and
+// nests an app box (namespace+app) inside its cluster box when both apply. The
+// hierarchy is two levels deep (cluster box, then app box); no separate namespace
+// box is generated.
+func groupByCluster(nodes *[]*NodeWrapper) {
+	clusterBox := make(map[string][]*NodeData)
+
+	for _, nw := range *nodes {
+		if nw.Data.Cluster != "" {
+			k := fmt.Sprintf("box_cluster_%s", nw.Data.Cluster)
+			clusterBox[k] = append(clusterBox[k], nw.Data)
+		}
+	}
+
+	clusterParents := generateGroupCompoundNodes(clusterBox, nodes, options.GroupByCluster, "")
+
+	appBox := make(map[string][]*NodeData)
+	for _, nw := range *nodes {
+		if nw.Data.Cluster != "" && nw.Data.IsGroup == "" && nw.Data.App != "unknown" && nw.Data.App != "" {
+			k := fmt.Sprintf("box_%s_%s_%s", nw.Data.Cluster, nw.Data.Namespace, nw.Data.App)
+			appBox[k] = append(appBox[k], nw.Data)
+		}
+	}
+
 	for k, members := range appBox {
+		clusterParent := clusterParents[fmt.Sprintf("box_cluster_%s", members[0].Cluster)]
+		generateGroupCompoundNodes(map[string][]*NodeData{k: members}, nodes, options.GroupByApp, clusterParent)
+	}
+}
+
+// generateGroupCompoundNodes creates a compound (parent) node for each box with more
+// than one member, optionally nesting it under an existing compound node identified
+// by parent (e.g. an app box nested inside a cluster box). It returns the box key to
+// generated node ID mapping so callers can nest further boxes under these parents.
+func generateGroupCompoundNodes(boxes map[string][]*NodeData, nodes *[]*NodeWrapper, groupBy string, parent string) map[string]string {
+	parentIds := make(map[string]string)
+
+	for k, members := range boxes {
 		if len(members) > 1 {
 			// create the compound (parent) node for the member nodes
 			nodeId := nodeHash(k)
+			parentIds[k] = nodeId
 			nd := NodeData{
 				Id:        nodeId,
 				NodeType:  graph.NodeTypeApp,
+				Cluster:   members[0].Cluster,
 				Namespace: members[0].Namespace,
 				App:       members[0].App,
 				Version:   "",
 				IsGroup:   groupBy,
+				Parent:    parent,
+			}
+
+			// a cluster box spans every namespace (and app) in that cluster, so
+			// members[0] is not a representative member the way it is for an
+			// app/version box keyed on a single (namespace, app) pair
+			if groupBy == options.GroupByCluster {
+				nd.Namespace = ""
+				nd.App = ""
 			}
 
 			nw := NodeWrapper{
@@ -419,4 +654,232 @@ func generateGroupCompoundNodes(appBox map[string][]*NodeData, nodes *[]*NodeWra
 			*nodes = append(*nodes, &nw)
 		}
 	}
+
+	return parentIds
+}
+
+// Delta is the result of diffing two successive BuildElements snapshots of the
+// same graph, keyed by the stable nodeHash/edgeHash identities. It lets the
+// browser patch its Cytoscape model instead of re-laying out the whole graph
+// on every refresh cycle.
+type Delta struct {
+	AddedNodes   []*NodeWrapper `json:"addedNodes,omitempty"`
+	RemovedNodes []string       `json:"removedNodes,omitempty"` // removed NodeData.Id values
+	UpdatedNodes []*NodeUpdate  `json:"updatedNodes,omitempty"`
+	AddedEdges   []*EdgeWrapper `json:"addedEdges,omitempty"`
+	RemovedEdges []string       `json:"removedEdges,omitempty"` // removed EdgeData.Id values
+	UpdatedEdges []*EdgeUpdate  `json:"updatedEdges,omitempty"`
+}
+
+// NodeUpdate carries only the node fields that changed between two snapshots
+// of the same node (identified by Id). Unchanged fields are left as nil so
+// they're omitted from the JSON patch.
+type NodeUpdate struct {
+	Id               string  `json:"id"`
+	HttpIn           *string `json:"httpIn,omitempty"`
+	HttpIn3xx        *string `json:"httpIn3XX,omitempty"`
+	HttpIn4xx        *string `json:"httpIn4XX,omitempty"`
+	HttpIn5xx        *string `json:"httpIn5XX,omitempty"`
+	HttpOut          *string `json:"httpOut,omitempty"`
+	TcpIn            *string `json:"tcpIn,omitempty"`
+	TcpOut           *string `json:"tcpOut,omitempty"`
+	HasCB            *bool   `json:"hasCB,omitempty"`
+	HasMissingSC     *bool   `json:"hasMissingSC,omitempty"`
+	HasVS            *bool   `json:"hasVS,omitempty"`
+	IsDead           *bool   `json:"isDead,omitempty"`
+	IsUnused         *bool   `json:"isUnused,omitempty"`
+	OutlierDetection *bool   `json:"outlierDetection,omitempty"`
+}
+
+// EdgeUpdate carries only the edge fields that changed between two snapshots
+// of the same edge (identified by Id).
+type EdgeUpdate struct {
+	Id                      string                   `json:"id"`
+	Http                    *string                  `json:"http,omitempty"`
+	Http3xx                 *string                  `json:"http3XX,omitempty"`
+	Http4xx                 *string                  `json:"http4XX,omitempty"`
+	Http5xx                 *string                  `json:"http5XX,omitempty"`
+	HttpPercentErr          *string                  `json:"httpPercentErr,omitempty"`
+	HttpPercentReq          *string                  `json:"httpPercentReq,omitempty"`
+	ResponseTime            *string                  `json:"responseTime,omitempty"`
+	ResponseTimePercentiles *ResponseTimePercentiles `json:"responseTimePercentiles,omitempty"`
+	Tcp                     *string                  `json:"tcp,omitempty"`
+	IsMTLS                  *bool                    `json:"isMTLS,omitempty"`
+	IsUnused                *bool                    `json:"isUnused,omitempty"`
+	IsCrossCluster          *bool                    `json:"isCrossCluster,omitempty"`
+	DestPeer                *string                  `json:"destPeer,omitempty"`
+	LBPolicy                *string                  `json:"lbPolicy,omitempty"`
+	HashPolicies            []HashPolicy             `json:"hashPolicies,omitempty"`
+}
+
+// NewDelta diffs two successive traffic map snapshots of the same graph and
+// returns the additions, removals and field-level updates needed to patch a
+// previously rendered Cytoscape model.
+func NewDelta(prev, curr graph.TrafficMap, o options.VendorOptions) Delta {
+	prevEl := BuildElements(prev, o)
+	currEl := BuildElements(curr, o)
+
+	prevNodes := indexNodesById(prevEl.Nodes)
+	currNodes := indexNodesById(currEl.Nodes)
+	prevEdges := indexEdgesById(prevEl.Edges)
+	currEdges := indexEdgesById(currEl.Edges)
+
+	delta := Delta{}
+
+	for id, n := range currNodes {
+		if _, ok := prevNodes[id]; !ok {
+			delta.AddedNodes = append(delta.AddedNodes, &NodeWrapper{Data: n})
+		} else if u := diffNode(prevNodes[id], n); u != nil {
+			delta.UpdatedNodes = append(delta.UpdatedNodes, u)
+		}
+	}
+	for id, n := range prevNodes {
+		if _, ok := currNodes[id]; !ok {
+			delta.RemovedNodes = append(delta.RemovedNodes, n.Id)
+		}
+	}
+
+	for id, e := range currEdges {
+		if _, ok := prevEdges[id]; !ok {
+			delta.AddedEdges = append(delta.AddedEdges, &EdgeWrapper{Data: e})
+		} else if u := diffEdge(prevEdges[id], e); u != nil {
+			delta.UpdatedEdges = append(delta.UpdatedEdges, u)
+		}
+	}
+	for id, e := range prevEdges {
+		if _, ok := currEdges[id]; !ok {
+			delta.RemovedEdges = append(delta.RemovedEdges, e.Id)
+		}
+	}
+
+	return delta
+}
+
+func indexNodesById(nodes []*NodeWrapper) map[string]*NodeData {
+	idx := make(map[string]*NodeData, len(nodes))
+	for _, n := range nodes {
+		idx[n.Data.Id] = n.Data
+	}
+	return idx
+}
+
+func indexEdgesById(edges []*EdgeWrapper) map[string]*EdgeData {
+	idx := make(map[string]*EdgeData, len(edges))
+	for _, e := range edges {
+		idx[e.Data.Id] = e.Data
+	}
+	return idx
+}
+
+// diffNode returns the fields that changed between prev and curr, or nil if
+// nothing relevant to a live view changed.
+func diffNode(prev, curr *NodeData) *NodeUpdate {
+	u := &NodeUpdate{Id: curr.Id}
+	changed := false
+
+	if prev.HttpIn != curr.HttpIn {
+		u.HttpIn, changed = &curr.HttpIn, true
+	}
+	if prev.HttpIn3xx != curr.HttpIn3xx {
+		u.HttpIn3xx, changed = &curr.HttpIn3xx, true
+	}
+	if prev.HttpIn4xx != curr.HttpIn4xx {
+		u.HttpIn4xx, changed = &curr.HttpIn4xx, true
+	}
+	if prev.HttpIn5xx != curr.HttpIn5xx {
+		u.HttpIn5xx, changed = &curr.HttpIn5xx, true
+	}
+	if prev.HttpOut != curr.HttpOut {
+		u.HttpOut, changed = &curr.HttpOut, true
+	}
+	if prev.TcpIn != curr.TcpIn {
+		u.TcpIn, changed = &curr.TcpIn, true
+	}
+	if prev.TcpOut != curr.TcpOut {
+		u.TcpOut, changed = &curr.TcpOut, true
+	}
+	if prev.HasCB != curr.HasCB {
+		u.HasCB, changed = &curr.HasCB, true
+	}
+	if prev.HasMissingSC != curr.HasMissingSC {
+		u.HasMissingSC, changed = &curr.HasMissingSC, true
+	}
+	if prev.HasVS != curr.HasVS {
+		u.HasVS, changed = &curr.HasVS, true
+	}
+	if prev.IsDead != curr.IsDead {
+		u.IsDead, changed = &curr.IsDead, true
+	}
+	if prev.IsUnused != curr.IsUnused {
+		u.IsUnused, changed = &curr.IsUnused, true
+	}
+	if prev.OutlierDetection != curr.OutlierDetection {
+		u.OutlierDetection, changed = &curr.OutlierDetection, true
+	}
+
+	if !changed {
+		return nil
+	}
+	return u
+}
+
+// diffEdge returns the fields that changed between prev and curr, or nil if
+// nothing relevant to a live view changed.
+func diffEdge(prev, curr *EdgeData) *EdgeUpdate {
+	u := &EdgeUpdate{Id: curr.Id}
+	changed := false
+
+	if prev.Http != curr.Http {
+		u.Http, changed = &curr.Http, true
+	}
+	if prev.Http3xx != curr.Http3xx {
+		u.Http3xx, changed = &curr.Http3xx, true
+	}
+	if prev.Http4xx != curr.Http4xx {
+		u.Http4xx, changed = &curr.Http4xx, true
+	}
+	if prev.Http5xx != curr.Http5xx {
+		u.Http5xx, changed = &curr.Http5xx, true
+	}
+	if prev.HttpPercentErr != curr.HttpPercentErr {
+		u.HttpPercentErr, changed = &curr.HttpPercentErr, true
+	}
+	if prev.HttpPercentReq != curr.HttpPercentReq {
+		u.HttpPercentReq, changed = &curr.HttpPercentReq, true
+	}
+	if prev.ResponseTime != curr.ResponseTime {
+		u.ResponseTime, changed = &curr.ResponseTime, true
+	}
+	if curr.ResponseTimePercentiles != nil && (prev.ResponseTimePercentiles == nil || *prev.ResponseTimePercentiles != *curr.ResponseTimePercentiles) {
+		u.ResponseTimePercentiles, changed = curr.ResponseTimePercentiles, true
+	} else if curr.ResponseTimePercentiles == nil && prev.ResponseTimePercentiles != nil {
+		// percentiles went away: send an empty set, as cleared string fields send ""
+		u.ResponseTimePercentiles, changed = &ResponseTimePercentiles{}, true
+	}
+	if prev.Tcp != curr.Tcp {
+		u.Tcp, changed = &curr.Tcp, true
+	}
+	if prev.IsMTLS != curr.IsMTLS {
+		u.IsMTLS, changed = &curr.IsMTLS, true
+	}
+	if prev.IsUnused != curr.IsUnused {
+		u.IsUnused, changed = &curr.IsUnused, true
+	}
+	if prev.IsCrossCluster != curr.IsCrossCluster {
+		u.IsCrossCluster, changed = &curr.IsCrossCluster, true
+	}
+	if prev.DestPeer != curr.DestPeer {
+		u.DestPeer, changed = &curr.DestPeer, true
+	}
+	if prev.LBPolicy != curr.LBPolicy {
+		u.LBPolicy, changed = &curr.LBPolicy, true
+	}
+	if !reflect.DeepEqual(prev.HashPolicies, curr.HashPolicies) {
+		u.HashPolicies, changed = curr.HashPolicies, true
+	}
+
+	if !changed {
+		return nil
+	}
+	return u
 }
@@ -0,0 +1,226 @@
+package cytoscape
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/options"
+)
+
+func TestNodeDepths(t *testing.T) {
+	root := &NodeWrapper{Data: &NodeData{Id: "root"}}
+	mid := &NodeWrapper{Data: &NodeData{Id: "mid", Parent: "root"}}
+	leaf := &NodeWrapper{Data: &NodeData{Id: "leaf", Parent: "mid"}}
+	orphan := &NodeWrapper{Data: &NodeData{Id: "orphan", Parent: "missing"}} // parent not in the node set
+
+	depth := nodeDepths([]*NodeWrapper{root, mid, leaf, orphan})
+
+	want := map[string]int{"root": 0, "mid": 1, "leaf": 2, "orphan": 0}
+	for id, w := range want {
+		if depth[id] != w {
+			t.Errorf("depth[%q] = %d, want %d", id, depth[id], w)
+		}
+	}
+}
+
+// TestBuildElementsClusterBoxSortsBeforeChildren guards the kiali-1258 sort
+// invariant for a cluster box that spans more than one namespace: the cluster
+// compound node must sort before every node nested under it, regardless of
+// which namespace happens to be iterated first when building the box.
+func TestBuildElementsClusterBoxSortsBeforeChildren(t *testing.T) {
+	trafficMap := graph.TrafficMap{
+		"n1": {ID: "n1", NodeType: graph.NodeTypeApp, Namespace: "ns-a", App: "app1",
+			Metadata: map[string]interface{}{"cluster": "east"}},
+		"n2": {ID: "n2", NodeType: graph.NodeTypeApp, Namespace: "ns-z", App: "app2",
+			Metadata: map[string]interface{}{"cluster": "east"}},
+	}
+
+	el := BuildElements(trafficMap, options.VendorOptions{GroupBy: options.GroupByCluster})
+
+	byId := make(map[string]*NodeData, len(el.Nodes))
+	for _, n := range el.Nodes {
+		byId[n.Data.Id] = n.Data
+	}
+
+	for i, n := range el.Nodes {
+		if n.Data.Parent == "" {
+			continue
+		}
+		parentIdx := -1
+		for j, other := range el.Nodes {
+			if other.Data.Id == n.Data.Parent {
+				parentIdx = j
+				break
+			}
+		}
+		if parentIdx == -1 {
+			t.Fatalf("node %q references missing parent %q", n.Data.Id, n.Data.Parent)
+		}
+		if parentIdx >= i {
+			t.Errorf("parent %q (index %d) does not sort before child %q (index %d)", n.Data.Parent, parentIdx, n.Data.Id, i)
+		}
+	}
+
+	// the cluster box itself should not carry an arbitrary member's namespace
+	for _, n := range byId {
+		if n.IsGroup == options.GroupByCluster && n.Namespace != "" {
+			t.Errorf("cluster box %q has non-empty Namespace %q, want empty", n.Id, n.Namespace)
+		}
+	}
+}
+
+func TestToHashPolicies(t *testing.T) {
+	cases := map[string]struct {
+		val  interface{}
+		want []HashPolicy
+	}{
+		"well-formed": {
+			val: []interface{}{
+				map[string]interface{}{"field": "header", "key": "x-user", "terminal": true},
+				map[string]interface{}{"field": "cookie", "key": "session"},
+			},
+			want: []HashPolicy{
+				{Field: "header", Key: "x-user", Terminal: true},
+				{Field: "cookie", Key: "session"},
+			},
+		},
+		"not a slice": {
+			val:  "ring_hash",
+			want: nil,
+		},
+		"entries of the wrong shape are skipped": {
+			val:  []interface{}{"not-a-map", map[string]interface{}{"field": "header", "key": "x-user"}},
+			want: []HashPolicy{{Field: "header", Key: "x-user"}},
+		},
+		"nil": {
+			val:  nil,
+			want: nil,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := toHashPolicies(c.val)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("toHashPolicies(%#v) = %#v, want %#v", c.val, got, c.want)
+			}
+		})
+	}
+}
+
+func TestAddResponseTimePercentiles(t *testing.T) {
+	cases := map[string]struct {
+		md   map[string]interface{}
+		want *ResponseTimePercentiles
+	}{
+		"all quantiles present": {
+			md: map[string]interface{}{
+				"responseTimeP50": 10.4,
+				"responseTimeP90": 25.0,
+				"responseTimeP95": 30.6,
+				"responseTimeP99": 99.9,
+			},
+			want: &ResponseTimePercentiles{P50: "10", P90: "25", P95: "31", P99: "100"},
+		},
+		"only some quantiles present": {
+			md:   map[string]interface{}{"responseTimeP50": 5.0},
+			want: &ResponseTimePercentiles{P50: "5"},
+		},
+		"no quantiles present": {
+			md:   map[string]interface{}{"http": 12.0},
+			want: nil,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := addResponseTimePercentiles(c.md)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("addResponseTimePercentiles(%#v) = %#v, want %#v", c.md, got, c.want)
+			}
+		})
+	}
+}
+
+func TestDiffNode(t *testing.T) {
+	prev := &NodeData{Id: "n1", HttpIn: "1.00", OutlierDetection: false}
+	curr := &NodeData{Id: "n1", HttpIn: "1.00", OutlierDetection: true}
+
+	u := diffNode(prev, curr)
+	if u == nil {
+		t.Fatal("diffNode returned nil, want an update for the OutlierDetection change")
+	}
+	if u.OutlierDetection == nil || *u.OutlierDetection != true {
+		t.Errorf("OutlierDetection update = %v, want true", u.OutlierDetection)
+	}
+	if u.HttpIn != nil {
+		t.Errorf("HttpIn update = %v, want nil (unchanged)", u.HttpIn)
+	}
+
+	unchanged := &NodeData{Id: "n1", HttpIn: "1.00", OutlierDetection: false}
+	if got := diffNode(prev, unchanged); got != nil {
+		t.Errorf("diffNode(prev, unchanged) = %#v, want nil", got)
+	}
+}
+
+func TestDiffEdge(t *testing.T) {
+	prev := &EdgeData{Id: "e1", LBPolicy: "round_robin"}
+	curr := &EdgeData{Id: "e1", LBPolicy: "ring_hash", HashPolicies: []HashPolicy{{Field: "header", Key: "x-user"}}}
+
+	u := diffEdge(prev, curr)
+	if u == nil {
+		t.Fatal("diffEdge returned nil, want an update for the LBPolicy/HashPolicies change")
+	}
+	if u.LBPolicy == nil || *u.LBPolicy != "ring_hash" {
+		t.Errorf("LBPolicy update = %v, want \"ring_hash\"", u.LBPolicy)
+	}
+	if !reflect.DeepEqual(u.HashPolicies, curr.HashPolicies) {
+		t.Errorf("HashPolicies update = %#v, want %#v", u.HashPolicies, curr.HashPolicies)
+	}
+
+	unchanged := &EdgeData{Id: "e1", LBPolicy: "round_robin"}
+	if got := diffEdge(prev, unchanged); got != nil {
+		t.Errorf("diffEdge(prev, unchanged) = %#v, want nil", got)
+	}
+}
+
+// TestNewDeltaConfigFieldsPatchWithoutSnapshot guards against the bug this
+// series of fixes addressed: a DestinationRule's LB policy / outlier config
+// changing between two polls must show up in the delta's UpdatedNodes /
+// UpdatedEdges, not only in the next full snapshot.
+func TestNewDeltaConfigFieldsPatchWithoutSnapshot(t *testing.T) {
+	newTrafficMap := func(outlier bool, lbPolicy string, withC bool) graph.TrafficMap {
+		a := &graph.Node{ID: "a", NodeType: graph.NodeTypeApp, App: "a",
+			Metadata: map[string]interface{}{"hasOutlierDetection": outlier}}
+		b := &graph.Node{ID: "b", NodeType: graph.NodeTypeApp, App: "b", Metadata: map[string]interface{}{}}
+		a.Edges = []*graph.Edge{{Source: a, Dest: b, Metadata: map[string]interface{}{"lbPolicy": lbPolicy}}}
+
+		tm := graph.TrafficMap{"a": a, "b": b}
+		if withC {
+			c := &graph.Node{ID: "c", NodeType: graph.NodeTypeApp, App: "c", Metadata: map[string]interface{}{}}
+			a.Edges = append(a.Edges, &graph.Edge{Source: a, Dest: c, Metadata: map[string]interface{}{}})
+			tm["c"] = c
+		}
+		return tm
+	}
+
+	prev := newTrafficMap(false, "round_robin", false)
+	curr := newTrafficMap(true, "ring_hash", true)
+
+	delta := NewDelta(prev, curr, options.VendorOptions{})
+
+	if len(delta.AddedNodes) != 1 || delta.AddedNodes[0].Data.App != "c" {
+		t.Errorf("AddedNodes = %#v, want exactly the new node c", delta.AddedNodes)
+	}
+	if len(delta.AddedEdges) != 1 {
+		t.Errorf("AddedEdges = %#v, want exactly the new a->c edge", delta.AddedEdges)
+	}
+
+	if len(delta.UpdatedNodes) != 1 || delta.UpdatedNodes[0].OutlierDetection == nil || !*delta.UpdatedNodes[0].OutlierDetection {
+		t.Errorf("UpdatedNodes = %#v, want node a patched with OutlierDetection=true", delta.UpdatedNodes)
+	}
+	if len(delta.UpdatedEdges) != 1 || delta.UpdatedEdges[0].LBPolicy == nil || *delta.UpdatedEdges[0].LBPolicy != "ring_hash" {
+		t.Errorf("UpdatedEdges = %#v, want the a->b edge patched with LBPolicy=ring_hash", delta.UpdatedEdges)
+	}
+}
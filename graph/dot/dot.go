@@ -0,0 +1,96 @@
+// Package dot renders a graph's shared cytoscape.Elements model as GraphViz
+// DOT, for offline analysis of Kiali graphs in GraphViz without having to
+// reverse-engineer the Cytoscape JSON.
+package dot
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/kiali/kiali/graph/cytoscape"
+	"github.com/kiali/kiali/graph/options"
+)
+
+// Renderer renders a cytoscape.Elements model as GraphViz DOT.
+type Renderer struct{}
+
+// Render implements options.Renderer.
+func (Renderer) Render(elements interface{}, o options.VendorOptions) ([]byte, error) {
+	el, ok := elements.(cytoscape.Elements)
+	if !ok {
+		return nil, fmt.Errorf("dot: unsupported elements type %T", elements)
+	}
+
+	// index nodes by Cytoscape Parent so compound (box) nodes become DOT subgraphs
+	children := make(map[string][]*cytoscape.NodeWrapper)
+	for _, n := range el.Nodes {
+		children[n.Data.Parent] = append(children[n.Data.Parent], n)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("digraph G {\n")
+	writeNodes(&buf, "", children, 1)
+
+	for _, e := range el.Edges {
+		fmt.Fprintf(&buf, "  %q -> %q [label=%q, color=%q];\n", e.Data.Source, e.Data.Target, edgeLabel(e.Data), edgeColor(e.Data))
+	}
+
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+func writeNodes(buf *bytes.Buffer, parent string, children map[string][]*cytoscape.NodeWrapper, depth int) {
+	indent := bytes.Repeat([]byte("  "), depth)
+
+	for _, n := range children[parent] {
+		// compound (box) nodes render as a labeled subgraph containing their members
+		if n.Data.IsGroup != "" {
+			fmt.Fprintf(buf, "%ssubgraph \"cluster_%s\" {\n", indent, n.Data.Id)
+			fmt.Fprintf(buf, "%s  label=%q;\n", indent, nodeLabel(n.Data))
+			writeNodes(buf, n.Data.Id, children, depth+1)
+			fmt.Fprintf(buf, "%s}\n", indent)
+			continue
+		}
+		fmt.Fprintf(buf, "%s%q [label=%q];\n", indent, n.Data.Id, nodeLabel(n.Data))
+	}
+}
+
+func nodeLabel(nd *cytoscape.NodeData) string {
+	switch {
+	case nd.Workload != "" && nd.Version != "":
+		return fmt.Sprintf("%s\n%s", nd.Workload, nd.Version)
+	case nd.App != "":
+		return nd.App
+	default:
+		return nd.Service
+	}
+}
+
+func edgeLabel(ed *cytoscape.EdgeData) string {
+	if ed.Http == "" {
+		return ""
+	}
+	if ed.HttpPercentErr != "" {
+		return fmt.Sprintf("%s rps, %s%% err", ed.Http, ed.HttpPercentErr)
+	}
+	return fmt.Sprintf("%s rps", ed.Http)
+}
+
+// edgeColor keys the edge's color to its error rate, so a DOT render
+// highlights unhealthy edges without the Cytoscape UI.
+func edgeColor(ed *cytoscape.EdgeData) string {
+	errPct, _ := strconv.ParseFloat(ed.HttpPercentErr, 64)
+	switch {
+	case errPct >= 20.0:
+		return "red"
+	case errPct > 0.0:
+		return "orange"
+	default:
+		return "black"
+	}
+}
+
+func init() {
+	options.RegisterRenderer(options.FormatDot, Renderer{})
+}
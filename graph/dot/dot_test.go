@@ -0,0 +1,81 @@
+package dot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kiali/kiali/graph/cytoscape"
+	"github.com/kiali/kiali/graph/options"
+)
+
+func TestRenderNestsCompoundNodeAsSubgraph(t *testing.T) {
+	el := cytoscape.Elements{
+		Nodes: []*cytoscape.NodeWrapper{
+			{Data: &cytoscape.NodeData{Id: "box1", App: "reviews", IsGroup: options.GroupByVersion}},
+			{Data: &cytoscape.NodeData{Id: "n1", Parent: "box1", Workload: "reviews-v1", Version: "v1"}},
+		},
+	}
+
+	out, err := (Renderer{}).Render(el, options.VendorOptions{})
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	got := string(out)
+	if !strings.Contains(got, `subgraph "cluster_box1"`) {
+		t.Errorf("Render output missing compound subgraph, got:\n%s", got)
+	}
+	if !strings.Contains(got, `"n1"`) {
+		t.Errorf("Render output missing member node, got:\n%s", got)
+	}
+}
+
+func TestRenderUnsupportedElementsType(t *testing.T) {
+	if _, err := (Renderer{}).Render("not elements", options.VendorOptions{}); err == nil {
+		t.Error("Render with a non-Elements value should return an error")
+	}
+}
+
+func TestEdgeColor(t *testing.T) {
+	cases := map[string]struct {
+		httpPercentErr string
+		want           string
+	}{
+		"no errors":     {"", "black"},
+		"minor errors":  {"5.0", "orange"},
+		"major errors":  {"20.0", "red"},
+		"right at 20%":  {"20.0", "red"},
+		"just under 1%": {"0.1", "orange"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			ed := &cytoscape.EdgeData{HttpPercentErr: c.httpPercentErr}
+			if got := edgeColor(ed); got != c.want {
+				t.Errorf("edgeColor(%q) = %q, want %q", c.httpPercentErr, got, c.want)
+			}
+		})
+	}
+}
+
+func TestEdgeLabel(t *testing.T) {
+	cases := map[string]struct {
+		ed   *cytoscape.EdgeData
+		want string
+	}{
+		"no traffic":        {&cytoscape.EdgeData{}, ""},
+		"traffic, no error": {&cytoscape.EdgeData{Http: "12.34"}, "12.34 rps"},
+		"traffic with error": {
+			&cytoscape.EdgeData{Http: "12.34", HttpPercentErr: "5.0"},
+			"12.34 rps, 5.0% err",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := edgeLabel(c.ed); got != c.want {
+				t.Errorf("edgeLabel() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+// Package gexf renders a graph's shared cytoscape.Elements model as GEXF 1.3,
+// for offline analysis of Kiali graphs in Gephi.
+package gexf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kiali/kiali/graph/cytoscape"
+	"github.com/kiali/kiali/graph/options"
+)
+
+// Renderer renders a cytoscape.Elements model as GEXF 1.3.
+type Renderer struct{}
+
+// attrDef declares one typed GEXF <attribute>, identified by a stable index
+// that attvalues below reference via "for".
+type attrDef struct {
+	id    string
+	title string
+	typ   string // "string" | "boolean" | "float"
+}
+
+// nodeAttrs covers every NodeData field, in field declaration order, except
+// Id and Parent: those are structural (the GEXF <node> id and an implicit
+// grouping the box/compound hierarchy already encodes via shared attribute
+// values), not graph data.
+var nodeAttrs = []attrDef{
+	{"0", "nodeType", "string"},
+	{"1", "cluster", "string"},
+	{"2", "namespace", "string"},
+	{"3", "workload", "string"},
+	{"4", "app", "string"},
+	{"5", "version", "string"},
+	{"6", "service", "string"},
+	{"7", "destServices", "string"}, // comma-joined service names
+	{"8", "httpIn", "float"},
+	{"9", "httpIn3XX", "float"},
+	{"10", "httpIn4XX", "float"},
+	{"11", "httpIn5XX", "float"},
+	{"12", "httpOut", "float"},
+	{"13", "tcpIn", "float"},
+	{"14", "tcpOut", "float"},
+	{"15", "hasCB", "boolean"},
+	{"16", "hasMissingSC", "boolean"},
+	{"17", "hasVS", "boolean"},
+	{"18", "isDead", "boolean"},
+	{"19", "isGroup", "string"},
+	{"20", "isInaccessible", "boolean"},
+	{"21", "isMisconfigured", "string"},
+	{"22", "isOutside", "boolean"},
+	{"23", "isRoot", "boolean"},
+	{"24", "isServiceEntry", "string"},
+	{"25", "isUnused", "boolean"},
+	{"26", "outlierDetection", "boolean"},
+}
+
+// edgeAttrs covers every EdgeData field, in field declaration order, except
+// Id, Source and Target: those are structural (the GEXF <edge> id/source/target).
+var edgeAttrs = []attrDef{
+	{"0", "http", "float"},
+	{"1", "http3XX", "float"},
+	{"2", "http4XX", "float"},
+	{"3", "http5XX", "float"},
+	{"4", "httpPercentErr", "float"},
+	{"5", "httpPercentReq", "float"},
+	{"6", "responseTime", "float"}, // deprecated mean, kept for backward compatibility
+	{"7", "responseTimeP50", "float"},
+	{"8", "responseTimeP90", "float"},
+	{"9", "responseTimeP95", "float"},
+	{"10", "responseTimeP99", "float"},
+	{"11", "isMTLS", "boolean"},
+	{"12", "isUnused", "boolean"},
+	{"13", "tcp", "float"},
+	{"14", "isCrossCluster", "boolean"},
+	{"15", "destPeer", "string"},
+	{"16", "lbPolicy", "string"},
+	{"17", "hashPolicies", "string"}, // semicolon-joined "field:key[:terminal]" entries
+}
+
+// Render implements options.Renderer.
+func (Renderer) Render(elements interface{}, o options.VendorOptions) ([]byte, error) {
+	el, ok := elements.(cytoscape.Elements)
+	if !ok {
+		return nil, fmt.Errorf("gexf: unsupported elements type %T", elements)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString("<gexf xmlns=\"http://www.gexf.net/1.3\" version=\"1.3\">\n")
+	buf.WriteString("  <graph mode=\"static\" defaultedgetype=\"directed\">\n")
+
+	writeAttributeDefs(&buf, "node", nodeAttrs)
+	writeAttributeDefs(&buf, "edge", edgeAttrs)
+
+	buf.WriteString("    <nodes>\n")
+	for _, n := range el.Nodes {
+		writeNode(&buf, n.Data)
+	}
+	buf.WriteString("    </nodes>\n")
+
+	buf.WriteString("    <edges>\n")
+	for i, e := range el.Edges {
+		writeEdge(&buf, i, e.Data)
+	}
+	buf.WriteString("    </edges>\n")
+
+	buf.WriteString("  </graph>\n</gexf>\n")
+	return buf.Bytes(), nil
+}
+
+func writeAttributeDefs(buf *bytes.Buffer, class string, attrs []attrDef) {
+	fmt.Fprintf(buf, "    <attributes class=%q>\n", class)
+	for _, a := range attrs {
+		fmt.Fprintf(buf, "      <attribute id=%q title=%q type=%q/>\n", a.id, a.title, a.typ)
+	}
+	buf.WriteString("    </attributes>\n")
+}
+
+func writeNode(buf *bytes.Buffer, nd *cytoscape.NodeData) {
+	label := nd.App
+	if label == "" {
+		label = nd.Service
+	}
+	fmt.Fprintf(buf, "      <node id=%q label=%q>\n", nd.Id, escape(label))
+	buf.WriteString("        <attvalues>\n")
+	writeAttValue(buf, "0", nd.NodeType)
+	writeAttValue(buf, "1", nd.Cluster)
+	writeAttValue(buf, "2", nd.Namespace)
+	writeAttValue(buf, "3", nd.Workload)
+	writeAttValue(buf, "4", nd.App)
+	writeAttValue(buf, "5", nd.Version)
+	writeAttValue(buf, "6", nd.Service)
+	writeAttValue(buf, "7", joinKeys(nd.DestServices))
+	writeAttValue(buf, "8", nd.HttpIn)
+	writeAttValue(buf, "9", nd.HttpIn3xx)
+	writeAttValue(buf, "10", nd.HttpIn4xx)
+	writeAttValue(buf, "11", nd.HttpIn5xx)
+	writeAttValue(buf, "12", nd.HttpOut)
+	writeAttValue(buf, "13", nd.TcpIn)
+	writeAttValue(buf, "14", nd.TcpOut)
+	writeAttValue(buf, "15", fmt.Sprintf("%t", nd.HasCB))
+	writeAttValue(buf, "16", fmt.Sprintf("%t", nd.HasMissingSC))
+	writeAttValue(buf, "17", fmt.Sprintf("%t", nd.HasVS))
+	writeAttValue(buf, "18", fmt.Sprintf("%t", nd.IsDead))
+	writeAttValue(buf, "19", nd.IsGroup)
+	writeAttValue(buf, "20", fmt.Sprintf("%t", nd.IsInaccessible))
+	writeAttValue(buf, "21", nd.IsMisconfigured)
+	writeAttValue(buf, "22", fmt.Sprintf("%t", nd.IsOutside))
+	writeAttValue(buf, "23", fmt.Sprintf("%t", nd.IsRoot))
+	writeAttValue(buf, "24", nd.IsServiceEntry)
+	writeAttValue(buf, "25", fmt.Sprintf("%t", nd.IsUnused))
+	writeAttValue(buf, "26", fmt.Sprintf("%t", nd.OutlierDetection))
+	buf.WriteString("        </attvalues>\n")
+	buf.WriteString("      </node>\n")
+}
+
+func writeEdge(buf *bytes.Buffer, i int, ed *cytoscape.EdgeData) {
+	fmt.Fprintf(buf, "      <edge id=\"%d\" source=%q target=%q>\n", i, ed.Source, ed.Target)
+	buf.WriteString("        <attvalues>\n")
+	writeAttValue(buf, "0", ed.Http)
+	writeAttValue(buf, "1", ed.Http3xx)
+	writeAttValue(buf, "2", ed.Http4xx)
+	writeAttValue(buf, "3", ed.Http5xx)
+	writeAttValue(buf, "4", ed.HttpPercentErr)
+	writeAttValue(buf, "5", ed.HttpPercentReq)
+	writeAttValue(buf, "6", ed.ResponseTime)
+	if ed.ResponseTimePercentiles != nil {
+		writeAttValue(buf, "7", ed.ResponseTimePercentiles.P50)
+		writeAttValue(buf, "8", ed.ResponseTimePercentiles.P90)
+		writeAttValue(buf, "9", ed.ResponseTimePercentiles.P95)
+		writeAttValue(buf, "10", ed.ResponseTimePercentiles.P99)
+	}
+	writeAttValue(buf, "11", fmt.Sprintf("%t", ed.IsMTLS))
+	writeAttValue(buf, "12", fmt.Sprintf("%t", ed.IsUnused))
+	writeAttValue(buf, "13", ed.Tcp)
+	writeAttValue(buf, "14", fmt.Sprintf("%t", ed.IsCrossCluster))
+	writeAttValue(buf, "15", ed.DestPeer)
+	writeAttValue(buf, "16", ed.LBPolicy)
+	writeAttValue(buf, "17", joinHashPolicies(ed.HashPolicies))
+	buf.WriteString("        </attvalues>\n")
+	buf.WriteString("      </edge>\n")
+}
+
+func writeAttValue(buf *bytes.Buffer, id, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(buf, "          <attvalue for=%q value=%q/>\n", id, escape(value))
+}
+
+// joinKeys flattens a destServices-style set into GEXF's one-value-per-attribute
+// model as a sorted, comma-separated string.
+func joinKeys(set map[string]bool) string {
+	if len(set) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// joinHashPolicies flattens the consistent-hash routing keys into GEXF's
+// one-value-per-attribute model as a semicolon-separated "field:key" list,
+// with a trailing ":terminal" marker for terminal policies.
+func joinHashPolicies(policies []cytoscape.HashPolicy) string {
+	if len(policies) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(policies))
+	for _, p := range policies {
+		part := fmt.Sprintf("%s:%s", p.Field, p.Key)
+		if p.Terminal {
+			part += ":terminal"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ";")
+}
+
+func escape(s string) string {
+	var b bytes.Buffer
+	if err := xml.EscapeText(&b, []byte(s)); err != nil {
+		return s
+	}
+	return b.String()
+}
+
+func init() {
+	options.RegisterRenderer(options.FormatGexf, Renderer{})
+}
@@ -0,0 +1,101 @@
+package gexf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kiali/kiali/graph/cytoscape"
+	"github.com/kiali/kiali/graph/options"
+)
+
+func TestRenderProducesTypedAttributesAndValues(t *testing.T) {
+	el := cytoscape.Elements{
+		Nodes: []*cytoscape.NodeWrapper{
+			{Data: &cytoscape.NodeData{
+				Id: "n1", App: "reviews", Namespace: "default",
+				DestServices:     map[string]bool{"b": true, "a": true},
+				OutlierDetection: true,
+			}},
+		},
+		Edges: []*cytoscape.EdgeWrapper{
+			{Data: &cytoscape.EdgeData{
+				Source: "n1", Target: "n1", Http: "12.34",
+				LBPolicy:     "ring_hash",
+				HashPolicies: []cytoscape.HashPolicy{{Field: "header", Key: "x-user", Terminal: true}},
+			}},
+		},
+	}
+
+	out, err := (Renderer{}).Render(el, options.VendorOptions{})
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	got := string(out)
+
+	for _, want := range []string{
+		`<attribute id="7" title="destServices" type="string"/>`,
+		`<attribute id="26" title="outlierDetection" type="boolean"/>`,
+		`<attribute id="17" title="hashPolicies" type="string"/>`,
+		`value="a,b"`,
+		`value="true"`,
+		`value="header:x-user:terminal"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render output missing %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderUnsupportedElementsType(t *testing.T) {
+	if _, err := (Renderer{}).Render(42, options.VendorOptions{}); err == nil {
+		t.Error("Render with a non-Elements value should return an error")
+	}
+}
+
+func TestJoinKeys(t *testing.T) {
+	cases := map[string]struct {
+		set  map[string]bool
+		want string
+	}{
+		"empty":       {nil, ""},
+		"single":      {map[string]bool{"a": true}, "a"},
+		"sorted":      {map[string]bool{"b": true, "a": true, "c": true}, "a,b,c"},
+		"false value": {map[string]bool{"a": false}, "a"}, // presence, not truthiness, is what destServices tracks
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := joinKeys(c.set); got != c.want {
+				t.Errorf("joinKeys(%#v) = %q, want %q", c.set, got, c.want)
+			}
+		})
+	}
+}
+
+func TestJoinHashPolicies(t *testing.T) {
+	cases := map[string]struct {
+		policies []cytoscape.HashPolicy
+		want     string
+	}{
+		"empty": {nil, ""},
+		"one, non-terminal": {
+			[]cytoscape.HashPolicy{{Field: "cookie", Key: "session"}},
+			"cookie:session",
+		},
+		"two, one terminal": {
+			[]cytoscape.HashPolicy{
+				{Field: "header", Key: "x-user", Terminal: true},
+				{Field: "cookie", Key: "session"},
+			},
+			"header:x-user:terminal;cookie:session",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := joinHashPolicies(c.policies); got != c.want {
+				t.Errorf("joinHashPolicies(%#v) = %q, want %q", c.policies, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Package handler exposes the graph rendering pipeline over HTTP, selecting a
+// vendor renderer (cytoscape, dot, gexf) via the request's ?format= query
+// parameter so callers can fetch GraphViz DOT or GEXF without going through
+// the Cytoscape JSON.
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/cytoscape"
+	"github.com/kiali/kiali/graph/options"
+
+	// imported for their init() renderer registration (?format=dot|gexf)
+	_ "github.com/kiali/kiali/graph/dot"
+	_ "github.com/kiali/kiali/graph/gexf"
+)
+
+// TrafficMapFunc fetches the current traffic map for a namespace. Callers
+// wire this to the same business layer that builds TrafficMap elsewhere.
+type TrafficMapFunc func(namespace string, o options.VendorOptions) (graph.TrafficMap, error)
+
+// contentTypes gives each registered format selector a response Content-Type.
+var contentTypes = map[string]string{
+	options.FormatCytoscape: "application/json",
+	options.FormatDot:       "text/vnd.graphviz",
+	options.FormatGexf:      "application/gexf+xml",
+}
+
+// GraphHandler renders a namespace's graph in the vendor format selected by
+// the request's ?format= query parameter (one of "cytoscape", "dot", "gexf"),
+// defaulting to FormatCytoscape when the parameter is absent.
+type GraphHandler struct {
+	FetchTrafficMap TrafficMapFunc
+	Options         options.VendorOptions
+}
+
+// ServeHTTP implements http.Handler.
+func (h GraphHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = options.FormatCytoscape
+	}
+
+	renderer, ok := options.RendererFor(format)
+	if !ok {
+		http.Error(w, fmt.Sprintf("unsupported graph format %q", format), http.StatusBadRequest)
+		return
+	}
+
+	namespace := r.URL.Query().Get("namespace")
+	trafficMap, err := h.FetchTrafficMap(namespace, h.Options)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	body, err := renderer.Render(cytoscape.BuildElements(trafficMap, h.Options), h.Options)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if ct, ok := contentTypes[format]; ok {
+		w.Header().Set("Content-Type", ct)
+	}
+	w.Write(body)
+}
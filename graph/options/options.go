@@ -0,0 +1,56 @@
+// Package options defines the inputs used to shape how a graph is rendered.
+// These options are not specific to any single vendor (Cytoscape, DOT, GEXF, ...);
+// each vendor package reads the subset it needs from VendorOptions.
+package options
+
+import "time"
+
+// GroupBy identifies how compound (box) nodes are generated for the graph.
+const (
+	GroupByApp     = "app"
+	GroupByVersion = "version"
+	GroupByCluster = "cluster"
+)
+
+// Format identifies the vendor-specific representation a graph is rendered as,
+// selected via the graph request's ?format= query parameter.
+const (
+	FormatCytoscape = "cytoscape"
+	FormatDot       = "dot"
+	FormatGexf      = "gexf"
+)
+
+// VendorOptions are the graph generation options relevant to the vendor-specific
+// renderers (e.g. cytoscape.NewConfig).
+type VendorOptions struct {
+	Duration  time.Duration
+	GraphType string
+	GroupBy   string
+	QueryTime int64     // unix time in seconds, set to the time the graph was generated
+	Quantiles []float64 // response time quantiles to request from the Prometheus query layer, e.g. [0.5, 0.95, 0.99]
+}
+
+// Renderer converts the shared graph Elements model (produced by
+// cytoscape.BuildElements) into a vendor-specific byte representation, e.g.
+// Cytoscape JSON, GraphViz DOT, or GEXF. elements is declared as interface{}
+// rather than a concrete type to avoid this package importing graph/cytoscape,
+// which itself depends on options.
+type Renderer interface {
+	Render(elements interface{}, o VendorOptions) ([]byte, error)
+}
+
+var renderers = map[string]Renderer{}
+
+// RegisterRenderer registers a Renderer for the given format selector. Vendor
+// packages call this from an init() func so the HTTP handler can pick the
+// right renderer for a request's ?format= without importing every vendor
+// package directly.
+func RegisterRenderer(format string, r Renderer) {
+	renderers[format] = r
+}
+
+// RendererFor returns the Renderer registered for format, if any.
+func RendererFor(format string) (Renderer, bool) {
+	r, ok := renderers[format]
+	return r, ok
+}
@@ -0,0 +1,87 @@
+// Package stream provides an SSE handler that streams incremental graph
+// updates to subscribed clients, using cytoscape.NewDelta so the browser can
+// patch its Cytoscape model instead of replacing it on every refresh cycle.
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/cytoscape"
+	"github.com/kiali/kiali/graph/options"
+)
+
+// TrafficMapFunc fetches the current traffic map for a namespace. Callers
+// wire this to the same business layer the non-streaming graph handlers use.
+type TrafficMapFunc func(namespace string, o options.VendorOptions) (graph.TrafficMap, error)
+
+// Handler streams `delta` frames for a namespace at RefreshInterval, with a
+// full `snapshot` frame every SnapshotEvery ticks for reconciliation.
+type Handler struct {
+	FetchTrafficMap TrafficMapFunc
+	Options         options.VendorOptions
+	RefreshInterval time.Duration
+	SnapshotEvery   int
+}
+
+// ServeHTTP implements http.Handler, keeping the connection open and writing
+// SSE frames until the client disconnects. It expects a `namespace` query
+// parameter identifying which graph to subscribe to.
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	if h.RefreshInterval <= 0 {
+		http.Error(w, "stream: RefreshInterval must be positive", http.StatusInternalServerError)
+		return
+	}
+
+	namespace := r.URL.Query().Get("namespace")
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ticker := time.NewTicker(h.RefreshInterval)
+	defer ticker.Stop()
+
+	var prev graph.TrafficMap
+	tick := 0
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			curr, err := h.FetchTrafficMap(namespace, h.Options)
+			if err != nil {
+				continue
+			}
+
+			// SnapshotEvery <= 0 disables delta frames: every frame is a full snapshot.
+			if prev == nil || h.SnapshotEvery <= 0 || tick%h.SnapshotEvery == 0 {
+				writeFrame(w, flusher, "snapshot", cytoscape.NewConfig(curr, h.Options))
+			} else {
+				writeFrame(w, flusher, "delta", cytoscape.NewDelta(prev, curr, h.Options))
+			}
+
+			prev = curr
+			tick++
+		}
+	}
+}
+
+func writeFrame(w http.ResponseWriter, flusher http.Flusher, event string, payload interface{}) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, body)
+	flusher.Flush()
+}